@@ -0,0 +1,20 @@
+// Package announcements defines the kinds of events broadcast over the
+// control plane's internal pub/sub broker.
+package announcements
+
+// Kind is the type of an announcement.
+type Kind string
+
+// String returns the string representation of the announcement Kind.
+func (at Kind) String() string {
+	return string(at)
+}
+
+const (
+	// CertificateRotated is the kind of announcement emitted when a certificate has been rotated.
+	CertificateRotated Kind = "certificate-rotated"
+
+	// CertificateRotationFailed is the kind of announcement emitted when a scheduled certificate
+	// rotation failed and is being retried with backoff.
+	CertificateRotationFailed Kind = "certificate-rotation-failed"
+)
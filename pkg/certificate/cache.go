@@ -0,0 +1,71 @@
+package certificate
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrCacheMiss is returned by Cache.Get when no certificate is cached for the
+// given CommonName.
+var ErrCacheMiss = errors.New("certificate: cache miss")
+
+// Cache persists issued certificates so the Manager does not need to reissue
+// every certificate in the mesh each time it restarts. It is modeled on
+// acme/autocert.Cache.
+type Cache interface {
+	// Get returns the certificate cached under cn, or ErrCacheMiss if absent.
+	Get(ctx context.Context, cn CommonName) (*Certificate, error)
+
+	// Put stores cert under cn, overwriting any previous entry.
+	Put(ctx context.Context, cn CommonName, cert *Certificate) error
+
+	// Delete removes the certificate cached under cn. It is a no-op if absent.
+	Delete(ctx context.Context, cn CommonName) error
+
+	// List returns every certificate currently held by the cache.
+	List(ctx context.Context) ([]*Certificate, error)
+}
+
+// MemoryCache is the in-process, non-persistent Cache implementation: the
+// behavior the Manager had before Cache existed. Certificates do not survive
+// an osm-controller restart.
+type MemoryCache struct {
+	certs sync.Map // CommonName -> *Certificate
+}
+
+// NewMemoryCache returns a Cache that holds certificates only in memory.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{}
+}
+
+// Get implements Cache.
+func (c *MemoryCache) Get(_ context.Context, cn CommonName) (*Certificate, error) {
+	certInterface, ok := c.certs.Load(cn)
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	return certInterface.(*Certificate), nil
+}
+
+// Put implements Cache.
+func (c *MemoryCache) Put(_ context.Context, cn CommonName, cert *Certificate) error {
+	c.certs.Store(cn, cert)
+	return nil
+}
+
+// Delete implements Cache.
+func (c *MemoryCache) Delete(_ context.Context, cn CommonName) error {
+	c.certs.Delete(cn)
+	return nil
+}
+
+// List implements Cache.
+func (c *MemoryCache) List(_ context.Context) ([]*Certificate, error) {
+	var certs []*Certificate
+	c.certs.Range(func(_, certInterface interface{}) bool {
+		certs = append(certs, certInterface.(*Certificate))
+		return true
+	})
+	return certs, nil
+}
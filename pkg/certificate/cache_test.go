@@ -0,0 +1,152 @@
+package certificate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+const cacheTestNamespace = "osm-system"
+
+// newCacheImpls returns a fresh instance of every Cache implementation, so
+// the tests below can be run identically against each of them.
+func newCacheImpls(t *testing.T) map[string]Cache {
+	return map[string]Cache{
+		"MemoryCache": NewMemoryCache(),
+		"DirCache":    DirCache(t.TempDir()),
+		"SecretCache": NewSecretCache(k8sfake.NewSimpleClientset(), cacheTestNamespace),
+	}
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	for name, cache := range newCacheImpls(t) {
+		t.Run(name, func(t *testing.T) {
+			assert := tassert.New(t)
+			ctx := context.Background()
+			cn := CommonName("foo.bar.cluster.local")
+
+			_, err := cache.Get(ctx, cn)
+			assert.ErrorIs(err, ErrCacheMiss)
+
+			cert := &Certificate{
+				CommonName:   cn,
+				SerialNumber: "1",
+				CertChain:    []byte("chain"),
+				PrivateKey:   []byte("key"),
+				IssuingCA:    []byte("ca"),
+				Expiration:   time.Now().Add(time.Hour).Truncate(time.Second),
+			}
+			cert.cnPrefix = "foo"
+			cert.signingIssuerID = "id1"
+			cert.validatingIssuerID = "id1"
+
+			assert.NoError(cache.Put(ctx, cn, cert))
+
+			got, err := cache.Get(ctx, cn)
+			assert.NoError(err)
+			assert.Equal(cert.GetCommonName(), got.GetCommonName())
+			assert.Equal(cert.GetCertificateChain(), got.GetCertificateChain())
+			assert.Equal(cert.GetPrivateKey(), got.GetPrivateKey())
+			assert.Equal(cert.GetExpiration().Unix(), got.GetExpiration().Unix())
+			assert.Equal(cert.cnPrefix, got.cnPrefix)
+			assert.Equal(cert.signingIssuerID, got.signingIssuerID)
+			assert.Equal(cert.validatingIssuerID, got.validatingIssuerID)
+
+			list, err := cache.List(ctx)
+			assert.NoError(err)
+			assert.Len(list, 1)
+
+			assert.NoError(cache.Delete(ctx, cn))
+			_, err = cache.Get(ctx, cn)
+			assert.ErrorIs(err, ErrCacheMiss)
+
+			// Deleting an absent entry is a no-op, not an error.
+			assert.NoError(cache.Delete(ctx, cn))
+		})
+	}
+}
+
+func TestDirCacheCorruption(t *testing.T) {
+	assert := tassert.New(t)
+	dir := t.TempDir()
+	cache := DirCache(dir)
+	cn := CommonName("corrupt.cluster.local")
+
+	assert.NoError(os.WriteFile(cache.pathFor(cn), []byte("not json"), 0o600))
+
+	_, err := cache.Get(context.Background(), cn)
+	assert.Error(err)
+	assert.NotErrorIs(err, ErrCacheMiss)
+
+	_, err = cache.List(context.Background())
+	assert.Error(err)
+}
+
+func TestSecretCacheCorruption(t *testing.T) {
+	assert := tassert.New(t)
+	ctx := context.Background()
+	cache := NewSecretCache(k8sfake.NewSimpleClientset(), cacheTestNamespace)
+	cn := CommonName("corrupt.cluster.local")
+
+	cert := &Certificate{CommonName: cn}
+	assert.NoError(cache.Put(ctx, cn, cert))
+
+	secret, err := cache.kubeClient.CoreV1().Secrets(cacheTestNamespace).Get(ctx, secretNameForCN(cn), metav1.GetOptions{})
+	assert.NoError(err)
+	secret.Data[secretCertDataKey] = []byte("not json")
+	_, err = cache.kubeClient.CoreV1().Secrets(cacheTestNamespace).Update(ctx, secret, metav1.UpdateOptions{})
+	assert.NoError(err)
+
+	_, err = cache.Get(ctx, cn)
+	assert.Error(err)
+	assert.NotErrorIs(err, ErrCacheMiss)
+}
+
+func TestCacheConcurrentPut(t *testing.T) {
+	for name, cache := range newCacheImpls(t) {
+		t.Run(name, func(t *testing.T) {
+			assert := tassert.New(t)
+			ctx := context.Background()
+			cn := CommonName("concurrent.cluster.local")
+
+			const writers = 20
+			var wg sync.WaitGroup
+			wg.Add(writers)
+			for i := 0; i < writers; i++ {
+				go func(i int) {
+					defer wg.Done()
+					cert := &Certificate{CommonName: cn, SerialNumber: SerialNumber(string(rune('a' + i%26)))}
+					assert.NoError(cache.Put(ctx, cn, cert))
+				}(i)
+			}
+			wg.Wait()
+
+			got, err := cache.Get(ctx, cn)
+			assert.NoError(err)
+			assert.Equal(cn, got.GetCommonName())
+		})
+	}
+}
+
+// TestDirCachePersistsToDisk is a sanity check that DirCache actually
+// persists to disk rather than just holding an in-memory stand-in.
+func TestDirCachePersistsToDisk(t *testing.T) {
+	assert := tassert.New(t)
+	dir := t.TempDir()
+	cache := DirCache(dir)
+	cn := CommonName("ondisk.cluster.local")
+
+	assert.NoError(cache.Put(context.Background(), cn, &Certificate{CommonName: cn}))
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(err)
+	assert.Len(entries, 1)
+	assert.Equal(filepath.Ext(entries[0].Name()), ".json")
+}
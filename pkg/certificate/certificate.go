@@ -0,0 +1,163 @@
+// Package certificate manages the lifecycle of mTLS certificates issued to
+// proxies and control plane components in the mesh.
+package certificate
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
+)
+
+// CommonName is the Subject Common Name from a certificate.
+type CommonName string
+
+// SerialNumber is the serial number of a certificate.
+type SerialNumber string
+
+// Certificate represents an x509 certificate issued to a proxy or a control
+// plane component.
+type Certificate struct {
+	// CommonName is the Subject Common Name encoded in the certificate.
+	CommonName CommonName
+
+	// SerialNumber is the serial number of the certificate.
+	SerialNumber SerialNumber
+
+	// CertChain is the PEM-encoded certificate, including its intermediate chain.
+	CertChain pem.Certificate
+
+	// PrivateKey is the PEM-encoded private key of the certificate.
+	//
+	// This is empty for certificates issued from a CSR the manager did not
+	// generate the key for -- see IssueCertificateForCSR.
+	PrivateKey pem.PrivateKey
+
+	// IssuingCA is the root certificate of the issuer that signed this certificate.
+	IssuingCA pem.RootCertificate
+
+	// TrustedCAs is the bundle of CAs a peer presenting this certificate should
+	// be validated against. It may differ from IssuingCA during a root
+	// certificate rotation, when the signing and validating issuers differ.
+	TrustedCAs pem.RootCertificate
+
+	// Expiration is the time after which the certificate is no longer valid.
+	Expiration time.Time
+
+	// IssuedAt is when this certificate was issued, used together with
+	// Expiration to schedule its proactive rotation -- see RotationPolicy.
+	IssuedAt time.Time
+
+	// signingIssuerID is the ID of the issuer that signed this certificate, used
+	// to detect when the cached certificate was signed by an issuer that has
+	// since been rotated out.
+	signingIssuerID string
+
+	// validatingIssuerID is the ID of the issuer whose CA this certificate's
+	// peers should be validated against, used the same way as signingIssuerID.
+	validatingIssuerID string
+
+	// cnPrefix is the key this certificate is cached under -- the CN passed to
+	// IssueCertificate or derived from a CSR, before any trust domain suffix is
+	// appended. The rotor uses it to reissue under the same cache key.
+	cnPrefix string
+}
+
+// certificateJSON is the wire representation of Certificate used by Cache
+// implementations that persist certificates outside the process, letting the
+// otherwise-unexported bookkeeping fields survive a round-trip.
+type certificateJSON struct {
+	CommonName         CommonName
+	SerialNumber       SerialNumber
+	CertChain          pem.Certificate
+	PrivateKey         pem.PrivateKey
+	IssuingCA          pem.RootCertificate
+	TrustedCAs         pem.RootCertificate
+	Expiration         time.Time
+	IssuedAt           time.Time
+	SigningIssuerID    string
+	ValidatingIssuerID string
+	CNPrefix           string
+}
+
+// MarshalJSON implements json.Marshaler, including the unexported fields a
+// persistent Cache needs to validate and reissue a loaded certificate.
+func (c *Certificate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(certificateJSON{
+		CommonName:         c.CommonName,
+		SerialNumber:       c.SerialNumber,
+		CertChain:          c.CertChain,
+		PrivateKey:         c.PrivateKey,
+		IssuingCA:          c.IssuingCA,
+		TrustedCAs:         c.TrustedCAs,
+		Expiration:         c.Expiration,
+		IssuedAt:           c.IssuedAt,
+		SigningIssuerID:    c.signingIssuerID,
+		ValidatingIssuerID: c.validatingIssuerID,
+		CNPrefix:           c.cnPrefix,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, the counterpart to MarshalJSON.
+func (c *Certificate) UnmarshalJSON(data []byte) error {
+	var aux certificateJSON
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*c = Certificate{
+		CommonName:         aux.CommonName,
+		SerialNumber:       aux.SerialNumber,
+		CertChain:          aux.CertChain,
+		PrivateKey:         aux.PrivateKey,
+		IssuingCA:          aux.IssuingCA,
+		TrustedCAs:         aux.TrustedCAs,
+		Expiration:         aux.Expiration,
+		IssuedAt:           aux.IssuedAt,
+		signingIssuerID:    aux.SigningIssuerID,
+		validatingIssuerID: aux.ValidatingIssuerID,
+		cnPrefix:           aux.CNPrefix,
+	}
+	return nil
+}
+
+// GetCommonName returns the Subject Common Name of the certificate.
+func (c *Certificate) GetCommonName() CommonName {
+	return c.CommonName
+}
+
+// GetSerialNumber returns the serial number of the certificate.
+func (c *Certificate) GetSerialNumber() SerialNumber {
+	return c.SerialNumber
+}
+
+// GetCertificateChain returns the PEM-encoded certificate chain.
+func (c *Certificate) GetCertificateChain() pem.Certificate {
+	return c.CertChain
+}
+
+// GetPrivateKey returns the PEM-encoded private key of the certificate, if any.
+func (c *Certificate) GetPrivateKey() pem.PrivateKey {
+	return c.PrivateKey
+}
+
+// GetIssuingCA returns the root certificate that signed this certificate.
+func (c *Certificate) GetIssuingCA() pem.RootCertificate {
+	return c.IssuingCA
+}
+
+// GetTrustedCAs returns the bundle of CAs a peer presenting this certificate
+// should be validated against.
+func (c *Certificate) GetTrustedCAs() pem.RootCertificate {
+	return c.TrustedCAs
+}
+
+// GetExpiration returns the expiration time of the certificate.
+func (c *Certificate) GetExpiration() time.Time {
+	return c.Expiration
+}
+
+// ShouldRotate determines whether a certificate should be rotated.
+func (c *Certificate) ShouldRotate() bool {
+	return time.Now().After(c.Expiration)
+}
@@ -0,0 +1,109 @@
+package certificate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DirCache implements Cache by persisting each certificate as a JSON-encoded
+// file in a directory on disk, keyed by the SHA-256 hash of its CommonName.
+// It lets osm-controller survive a restart without re-issuing, and thereby
+// triggering a mass Envoy churn for, every certificate in the mesh.
+type DirCache string
+
+func (d DirCache) pathFor(cn CommonName) string {
+	sum := sha256.Sum256([]byte(cn))
+	return filepath.Join(string(d), hex.EncodeToString(sum[:])+".json")
+}
+
+// Get implements Cache.
+func (d DirCache) Get(_ context.Context, cn CommonName) (*Certificate, error) {
+	raw, err := os.ReadFile(d.pathFor(cn))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cert Certificate
+	if err := json.Unmarshal(raw, &cert); err != nil {
+		return nil, fmt.Errorf("%s: corrupt cache entry: %w", cn, err)
+	}
+	return &cert, nil
+}
+
+// Put implements Cache.
+func (d DirCache) Put(_ context.Context, cn CommonName, cert *Certificate) error {
+	if err := os.MkdirAll(string(d), 0o700); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(cert)
+	if err != nil {
+		return err
+	}
+
+	// Write to a uniquely-named temp file and rename it into place so a crash
+	// mid-write -- or a concurrent Put for the same CN -- never leaves a
+	// corrupt or half-written entry behind for a later Get to trip over.
+	tmp, err := os.CreateTemp(string(d), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), d.pathFor(cn))
+}
+
+// Delete implements Cache.
+func (d DirCache) Delete(_ context.Context, cn CommonName) error {
+	if err := os.Remove(d.pathFor(cn)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List implements Cache.
+func (d DirCache) List(_ context.Context) ([]*Certificate, error) {
+	entries, err := os.ReadDir(string(d))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []*Certificate
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		raw, err := os.ReadFile(filepath.Join(string(d), entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var cert Certificate
+		if err := json.Unmarshal(raw, &cert); err != nil {
+			return nil, fmt.Errorf("%s: corrupt cache entry: %w", entry.Name(), err)
+		}
+		certs = append(certs, &cert)
+	}
+	return certs, nil
+}
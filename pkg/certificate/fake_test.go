@@ -0,0 +1,67 @@
+package certificate
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
+)
+
+// fakeProvider is a test double for Provider. When err is true, Sign fails
+// with an error identifying the provider, mirroring a bad CA backend.
+type fakeProvider struct {
+	id  string
+	err bool
+}
+
+func (fp *fakeProvider) Bootstrap(_ context.Context) (pem.RootCertificate, pem.RootCertificate, error) {
+	return pem.RootCertificate(fp.id), nil, nil
+}
+
+func (fp *fakeProvider) Sign(_ context.Context, csr *x509.CertificateRequest, validityPeriod time.Duration) (*Certificate, error) {
+	if fp.err {
+		return nil, fmt.Errorf("%s failed", fp.id)
+	}
+
+	return &Certificate{
+		CommonName: CommonName(csr.Subject.CommonName),
+		Expiration: time.Now().Add(validityPeriod),
+	}, nil
+}
+
+func (fp *fakeProvider) ActiveIntermediate() pem.RootCertificate {
+	return pem.RootCertificate(fp.id)
+}
+
+func (fp *fakeProvider) GenerateIntermediate(_ context.Context) error {
+	return nil
+}
+
+func (fp *fakeProvider) Cleanup(_ context.Context) error {
+	return nil
+}
+
+// fakeMRCClient is a test double for MRCClient, returning a single, always-valid
+// "fake" provider (see this file's init).
+type fakeMRCClient struct{}
+
+func (f *fakeMRCClient) GetCertIssuerConfig() (string, map[string]string, string, error) {
+	return "fake", nil, "cluster.local", nil
+}
+
+func init() {
+	RegisterProvider("fake", func(map[string]string) (Provider, error) {
+		return &fakeProvider{id: "fake-ID"}, nil
+	})
+}
+
+// capturingMRCClient is a test double for MRCClient that returns a configurable kind.
+type capturingMRCClient struct {
+	kind string
+}
+
+func (c *capturingMRCClient) GetCertIssuerConfig() (string, map[string]string, string, error) {
+	return c.kind, nil, "cluster.local", nil
+}
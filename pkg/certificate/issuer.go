@@ -0,0 +1,15 @@
+package certificate
+
+import (
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
+)
+
+// issuer wraps a Provider with the bookkeeping the Manager needs to detect
+// when a cached certificate was signed or validated against a provider that
+// has since been rotated out, e.g. by an MRC update.
+type issuer struct {
+	Provider
+	ID                   string
+	CertificateAuthority pem.RootCertificate
+	TrustDomain          string
+}
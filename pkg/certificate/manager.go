@@ -0,0 +1,303 @@
+package certificate
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	stdpem "encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
+	"github.com/openservicemesh/osm/pkg/messaging"
+)
+
+// errNilCSR is returned when a CSR-based issuance is attempted with a nil CSR.
+var errNilCSR = errors.New("certificate: nil CSR")
+
+// errNilCertificate is returned when renewal is attempted against a nil certificate.
+var errNilCertificate = errors.New("certificate: nil certificate")
+
+// errInvalidCSRSignature is returned when a caller-submitted CSR's signature
+// does not verify, i.e. the caller has not proven possession of the private
+// key matching the public key it's asking to have certified.
+var errInvalidCSRSignature = errors.New("certificate: CSR signature verification failed")
+
+// Manager issues and tracks the lifecycle of the certificates used across the mesh.
+type Manager struct {
+	// cache persists certificates keyed by the unqualified CN (or CSR CN) they
+	// were requested under, so that a rotated issuer can still be matched back
+	// to the original request.
+	cache Cache
+
+	// signingIssuer is the Provider backing newly issued/rotated certificates.
+	signingIssuer *issuer
+
+	// validatingIssuer is the Provider whose CA peers should be validated
+	// against. It differs from signingIssuer only during a root certificate rotation.
+	validatingIssuer *issuer
+
+	// serviceCertValidityDuration is the default validity period used when an
+	// IssueOption does not override it.
+	serviceCertValidityDuration time.Duration
+
+	// mrcClient sources the signing/validating Provider configuration from
+	// MeshRootCertificate resources.
+	mrcClient MRCClient
+
+	// msgBroker is used to announce certificate lifecycle events, such as rotation.
+	msgBroker *messaging.Broker
+
+	// rotationMu guards rotationPolicy and rotationStates.
+	rotationMu sync.Mutex
+
+	// rotationPolicy is set by Start and nil until then; scheduleRotation is a
+	// no-op while it's nil.
+	rotationPolicy *RotationPolicy
+
+	// rotationStates tracks the scheduled rotation timer and failure count for
+	// each cnPrefix currently being proactively rotated.
+	rotationStates map[string]*rotationState
+}
+
+// NewManager creates a new certificate Manager, resolving its signing and
+// validating Provider by name via mrcClient and the RegisterProvider registry,
+// persisting issued certificates to cache.
+func NewManager(mrcClient MRCClient, cache Cache, validityPeriod time.Duration, msgBroker *messaging.Broker, opts ...ManagerOption) (*Manager, error) {
+	var managerOpts managerOptions
+	for _, opt := range opts {
+		opt(&managerOpts)
+	}
+
+	kind, options, trustDomain, err := mrcClient.GetCertIssuerConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching cert issuer config: %w", err)
+	}
+
+	activeIssuer, err := newIssuer(kind, mergeProviderOptions(options, managerOpts.providerOptions), trustDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Manager{
+		cache:                       cache,
+		signingIssuer:               activeIssuer,
+		validatingIssuer:            activeIssuer,
+		serviceCertValidityDuration: validityPeriod,
+		mrcClient:                   mrcClient,
+		msgBroker:                   msgBroker,
+	}, nil
+}
+
+// newIssuer constructs the registered Provider named kind and bootstraps it,
+// replacing the hard-wired provider-kind switch NewManager used to have.
+func newIssuer(kind string, options map[string]string, trustDomain string) (*issuer, error) {
+	factory, err := GetProviderFactory(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := factory(options)
+	if err != nil {
+		return nil, fmt.Errorf("error constructing %q certificate provider: %w", kind, err)
+	}
+
+	rootPEM, _, err := provider.Bootstrap(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("error bootstrapping %q certificate provider: %w", kind, err)
+	}
+
+	return &issuer{
+		Provider:             provider,
+		ID:                   kind + "/" + trustDomain,
+		CertificateAuthority: rootPEM,
+		TrustDomain:          trustDomain,
+	}, nil
+}
+
+// IssueCertificate issues a new certificate for the given CN prefix, generating
+// the private key internally, or returns a cached certificate that is still
+// signed and validated by the currently configured issuers.
+func (m *Manager) IssueCertificate(cnPrefix string, opts ...IssueOption) (*Certificate, error) {
+	return m.issueCertificate(context.Background(), cnPrefix, nil, false, opts...)
+}
+
+// IssueCertificateForCSR signs the given CSR, allowing the caller to generate
+// and retain the private key locally -- the returned Certificate never carries
+// a PrivateKey. csr's signature is verified before signing, proving the
+// caller actually holds the private key for the public key it's asking to
+// have certified. ctx threads through to the underlying Provider so
+// cancellations and deadlines are honored during issuer outages.
+func (m *Manager) IssueCertificateForCSR(ctx context.Context, csr *x509.CertificateRequest, opts ...IssueOption) (*Certificate, error) {
+	if csr == nil {
+		return nil, errNilCSR
+	}
+
+	return m.issueCertificate(ctx, csr.Subject.CommonName, csr, true, opts...)
+}
+
+// RenewContext renews peerCert, binding the new certificate to publicKey rather
+// than generating a new private key, and bypasses the cache so a fresh
+// certificate is always signed. The CSR built here is never signed -- unlike
+// IssueCertificateForCSR, the caller isn't proving fresh possession of
+// publicKey's private key, it's renewing an identity the Manager already
+// vouched for when it issued peerCert -- so issueCertificate is told not to
+// verify a CSR signature that doesn't exist.
+func (m *Manager) RenewContext(ctx context.Context, peerCert *Certificate, publicKey crypto.PublicKey) (*Certificate, error) {
+	if peerCert == nil {
+		return nil, errNilCertificate
+	}
+
+	cnPrefix := peerCert.cnPrefix
+	if cnPrefix == "" {
+		cnPrefix = string(peerCert.GetCommonName())
+	}
+	m.ReleaseCertificate(cnPrefix)
+
+	csr := &x509.CertificateRequest{
+		Subject:   pkix.Name{CommonName: string(peerCert.GetCommonName())},
+		PublicKey: publicKey,
+	}
+
+	return m.issueCertificate(ctx, cnPrefix, csr, false)
+}
+
+// issueCertificate backs all three issuance paths above so they share the same
+// cache keying and staleness validation: a cached certificate is only reused
+// if it was signed and is validated by the issuers currently configured on
+// the Manager. When csr is nil, a key pair and CSR are generated here, since
+// Provider.Sign only ever signs a CSR. verifyCSRSignature must be true for any
+// csr submitted by an external caller (IssueCertificateForCSR) so the CA
+// never signs a public key the submitter hasn't proven it holds the private
+// half of; it's false for csr == nil (generated here, from a key only this
+// call ever saw) and for RenewContext's synthetic, intentionally-unsigned CSR.
+func (m *Manager) issueCertificate(ctx context.Context, cnPrefix string, csr *x509.CertificateRequest, verifyCSRSignature bool, opts ...IssueOption) (*Certificate, error) {
+	options := issueOptions{validityPeriod: m.serviceCertValidityDuration}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	signingIssuer := m.signingIssuer
+	validatingIssuer := m.validatingIssuer
+
+	cached, err := m.cache.Get(ctx, CommonName(cnPrefix))
+	if err != nil && !errors.Is(err, ErrCacheMiss) {
+		return nil, fmt.Errorf("error reading certificate cache for %s: %w", cnPrefix, err)
+	}
+	if cached != nil && cached.signingIssuerID == signingIssuer.ID && cached.validatingIssuerID == validatingIssuer.ID {
+		return cached, nil
+	}
+
+	var privKey *ecdsa.PrivateKey
+	if csr == nil {
+		cn := CommonName(fmt.Sprintf("%s.%s", cnPrefix, signingIssuer.TrustDomain))
+		csr, privKey, err = newCSR(cn)
+		if err != nil {
+			return nil, fmt.Errorf("error generating key/CSR for %s: %w", cnPrefix, err)
+		}
+	}
+
+	if verifyCSRSignature {
+		if err := csr.CheckSignature(); err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", errInvalidCSRSignature, cnPrefix, err)
+		}
+	}
+
+	cert, err := signingIssuer.Sign(ctx, csr, options.validityPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	if privKey != nil {
+		keyPEM, err := encodeECPrivateKey(privKey)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding private key for %s: %w", cnPrefix, err)
+		}
+		cert.PrivateKey = keyPEM
+	} else {
+		// The key never left the requester -- do not let a Provider smuggle one back.
+		cert.PrivateKey = nil
+	}
+
+	cert.cnPrefix = cnPrefix
+	cert.IssuedAt = time.Now()
+	cert.signingIssuerID = signingIssuer.ID
+	cert.validatingIssuerID = validatingIssuer.ID
+	cert.IssuingCA = signingIssuer.CertificateAuthority
+
+	if validatingIssuer.ID == signingIssuer.ID {
+		cert.TrustedCAs = signingIssuer.CertificateAuthority
+	} else {
+		cert.TrustedCAs = append(append(pem.RootCertificate{}, signingIssuer.CertificateAuthority...), validatingIssuer.CertificateAuthority...)
+	}
+
+	if err := m.cache.Put(ctx, CommonName(cnPrefix), cert); err != nil {
+		return nil, fmt.Errorf("error caching certificate for %s: %w", cnPrefix, err)
+	}
+
+	m.scheduleRotation(cnPrefix, cert, 0)
+
+	return cert, nil
+}
+
+// newCSR generates an ECDSA key pair and a CSR for cn signed by it.
+func newCSR(cn CommonName) (*x509.CertificateRequest, *ecdsa.PrivateKey, error) {
+	privKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: string(cn)}}, privKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	csr, err := x509.ParseCertificateRequest(der)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return csr, privKey, nil
+}
+
+// encodeECPrivateKey PEM-encodes an ECDSA private key.
+func encodeECPrivateKey(key *ecdsa.PrivateKey) (pem.PrivateKey, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.PrivateKey(stdpem.EncodeToMemory(&stdpem.Block{Type: "EC PRIVATE KEY", Bytes: der})), nil
+}
+
+// getFromCache returns the cached certificate for cnPrefix, or nil if absent.
+func (m *Manager) getFromCache(cnPrefix string) *Certificate {
+	cert, err := m.cache.Get(context.Background(), CommonName(cnPrefix))
+	if err != nil {
+		return nil
+	}
+	return cert
+}
+
+// ReleaseCertificate removes a certificate from the cache.
+func (m *Manager) ReleaseCertificate(cnPrefix string) {
+	if err := m.cache.Delete(context.Background(), CommonName(cnPrefix)); err != nil {
+		log.Printf("certificate: error releasing %s: %s", cnPrefix, err)
+	}
+}
+
+// ListIssuedCertificates lists all currently cached certificates.
+func (m *Manager) ListIssuedCertificates() []*Certificate {
+	certs, err := m.cache.List(context.Background())
+	if err != nil {
+		log.Printf("certificate: error listing cache: %s", err)
+		return nil
+	}
+	return certs
+}
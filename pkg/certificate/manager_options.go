@@ -0,0 +1,68 @@
+package certificate
+
+// Well-known provider option keys. NewManager merges these into the options
+// map sourced from MRCClient.GetCertIssuerConfig before constructing the
+// signing/validating Provider, so a Provider that persists its private key at
+// rest (e.g. the tresor provider) can read them without the certificate
+// package needing to know which Provider is configured.
+const (
+	// OptionKeyPassphrase names the option a Provider reads its current CA
+	// private key passphrase from.
+	OptionKeyPassphrase = "keyPassphrase"
+
+	// OptionKeyPassphrasePrev names the option a Provider reads the previous
+	// CA private key passphrase from. A Provider that finds its key at rest
+	// can't be opened with OptionKeyPassphrase should fall back to this one,
+	// then re-wrap the key with OptionKeyPassphrase.
+	OptionKeyPassphrasePrev = "keyPassphrasePrev"
+)
+
+// ManagerOption configures construction-time behavior of a Manager that isn't
+// sourced from the MeshRootCertificate CR via MRCClient.
+type ManagerOption func(*managerOptions)
+
+type managerOptions struct {
+	providerOptions map[string]string
+}
+
+func (o *managerOptions) setProviderOption(key, value string) {
+	if o.providerOptions == nil {
+		o.providerOptions = make(map[string]string)
+	}
+	o.providerOptions[key] = value
+}
+
+// WithKeyPassphrase threads passphrase through to the signing Provider as
+// OptionKeyPassphrase, e.g. sourced by the caller from the OSM_CA_PASSPHRASE
+// environment variable.
+func WithKeyPassphrase(passphrase string) ManagerOption {
+	return func(o *managerOptions) {
+		o.setProviderOption(OptionKeyPassphrase, passphrase)
+	}
+}
+
+// WithKeyPassphrasePrev threads the previous passphrase through to the
+// signing Provider as OptionKeyPassphrasePrev, e.g. sourced by the caller from
+// the OSM_CA_PASSPHRASE_PREV environment variable during a passphrase
+// rotation.
+func WithKeyPassphrasePrev(passphrase string) ManagerOption {
+	return func(o *managerOptions) {
+		o.setProviderOption(OptionKeyPassphrasePrev, passphrase)
+	}
+}
+
+// mergeProviderOptions overlays overlay onto base, without mutating either.
+func mergeProviderOptions(base, overlay map[string]string) map[string]string {
+	if len(overlay) == 0 {
+		return base
+	}
+
+	merged := make(map[string]string, len(base)+len(overlay))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overlay {
+		merged[k] = v
+	}
+	return merged
+}
@@ -1,6 +1,12 @@
 package certificate
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"testing"
 	time "time"
 
@@ -11,102 +17,141 @@ import (
 	"github.com/openservicemesh/osm/pkg/messaging"
 )
 
-func TestRotor(t *testing.T) {
+func TestNewManagerThreadsProviderOptions(t *testing.T) {
 	assert := tassert.New(t)
 
-	cnPrefix := "foo"
-	validityPeriod := -1 * time.Hour // negative time means this cert has already expired -- will be rotated asap
+	var gotOptions map[string]string
+	RegisterProvider("options-capture", func(options map[string]string) (Provider, error) {
+		gotOptions = options
+		return &fakeProvider{id: "options-capture"}, nil
+	})
 
 	stop := make(chan struct{})
 	defer close(stop)
 	msgBroker := messaging.NewBroker(stop)
-	certManager, err := NewManager(&fakeMRCClient{}, validityPeriod, msgBroker)
-	certManager.Start(5*time.Second, stop)
-	assert.NoError(err)
-
-	certA, err := certManager.IssueCertificate(cnPrefix, WithValidityPeriod(validityPeriod))
-	assert.NoError(err)
-	certRotateChan := msgBroker.GetCertPubSub().Sub(announcements.CertificateRotated.String())
 
-	// Wait for two certificate rotations to be announced and terminate
-	<-certRotateChan
-	newCert, err := certManager.IssueCertificate(cnPrefix, WithValidityPeriod(validityPeriod))
+	_, err := NewManager(&capturingMRCClient{kind: "options-capture"}, NewMemoryCache(), time.Hour, msgBroker,
+		WithKeyPassphrase("current-passphrase"), WithKeyPassphrasePrev("previous-passphrase"))
 	assert.NoError(err)
-	assert.NotEqual(certA.GetExpiration(), newCert.GetExpiration())
-	assert.NotEqual(certA, newCert)
+	assert.Equal("current-passphrase", gotOptions[OptionKeyPassphrase])
+	assert.Equal("previous-passphrase", gotOptions[OptionKeyPassphrasePrev])
 }
 
-func TestReleaseCertificate(t *testing.T) {
-	cn := "Test CN"
-	cert := &Certificate{
-		CommonName: CommonName(cn),
-		Expiration: time.Now().Add(1 * time.Hour),
-	}
+func TestRotor(t *testing.T) {
+	for name, cache := range newCacheImpls(t) {
+		t.Run(name, func(t *testing.T) {
+			assert := tassert.New(t)
 
-	manager := &Manager{}
-	manager.cache.Store(cn, cert)
-
-	testCases := []struct {
-		name     string
-		cnPrefix string
-	}{
-		{
-			name:     "release existing certificate",
-			cnPrefix: cn,
-		},
-		{
-			name:     "release non-existing certificate",
-			cnPrefix: cn,
-		},
+			cnPrefix := "foo"
+			validityPeriod := -1 * time.Hour // negative time means this cert has already expired -- will be rotated asap
+
+			stop := make(chan struct{})
+			defer close(stop)
+			msgBroker := messaging.NewBroker(stop)
+			// The Manager's default validity period must be sane so that the
+			// certificate issued *by* the rotation itself doesn't also come
+			// back already-expired and trigger another immediate rotation --
+			// only the initial certificate below is deliberately born expired.
+			certManager, err := NewManager(&fakeMRCClient{}, cache, time.Hour, msgBroker)
+			assert.NoError(err)
+			certManager.Start(RotationPolicy{RenewBefore: 2.0 / 3.0, MinBackoff: time.Millisecond, MaxBackoff: time.Second}, stop)
+
+			certRotateChan := msgBroker.GetCertPubSub().Sub(announcements.CertificateRotated.String())
+
+			certA, err := certManager.IssueCertificate(cnPrefix, WithValidityPeriod(validityPeriod))
+			assert.NoError(err)
+
+			// Wait for a certificate rotation to be announced and terminate
+			<-certRotateChan
+			newCert := certManager.getFromCache(cnPrefix)
+			assert.NotNil(newCert)
+			assert.NotEqual(certA.GetExpiration(), newCert.GetExpiration())
+			assert.NotEqual(certA, newCert)
+		})
 	}
+}
+
+func TestReleaseCertificate(t *testing.T) {
+	for name, cache := range newCacheImpls(t) {
+		t.Run(name, func(t *testing.T) {
+			cn := "Test CN"
+			cert := &Certificate{
+				CommonName: CommonName(cn),
+				Expiration: time.Now().Add(1 * time.Hour),
+			}
 
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
+			manager := &Manager{cache: cache}
 			assert := tassert.New(t)
+			assert.NoError(cache.Put(context.Background(), CommonName(cn), cert))
+
+			testCases := []struct {
+				name     string
+				cnPrefix string
+			}{
+				{
+					name:     "release existing certificate",
+					cnPrefix: cn,
+				},
+				{
+					name:     "release non-existing certificate",
+					cnPrefix: cn,
+				},
+			}
+
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					assert := tassert.New(t)
 
-			manager.ReleaseCertificate(tc.cnPrefix)
-			cert := manager.getFromCache(tc.cnPrefix)
+					manager.ReleaseCertificate(tc.cnPrefix)
+					cert := manager.getFromCache(tc.cnPrefix)
 
-			assert.Nil(cert)
+					assert.Nil(cert)
+				})
+			}
 		})
 	}
 }
 
 func TestListIssuedCertificate(t *testing.T) {
-	assert := tassert.New(t)
+	for name, cache := range newCacheImpls(t) {
+		t.Run(name, func(t *testing.T) {
+			assert := tassert.New(t)
+			ctx := context.Background()
 
-	cn := CommonName("Test Cert")
-	cert := &Certificate{
-		CommonName: cn,
-	}
+			cn := CommonName("Test Cert")
+			cert := &Certificate{
+				CommonName: cn,
+			}
 
-	anotherCn := CommonName("Another Test Cert")
-	anotherCert := &Certificate{
-		CommonName: anotherCn,
-	}
+			anotherCn := CommonName("Another Test Cert")
+			anotherCert := &Certificate{
+				CommonName: anotherCn,
+			}
 
-	expectedCertificates := []*Certificate{cert, anotherCert}
+			expectedCertificates := []*Certificate{cert, anotherCert}
 
-	manager := &Manager{}
-	manager.cache.Store(cn, cert)
-	manager.cache.Store(anotherCn, anotherCert)
+			manager := &Manager{cache: cache}
+			assert.NoError(cache.Put(ctx, cn, cert))
+			assert.NoError(cache.Put(ctx, anotherCn, anotherCert))
 
-	cs := manager.ListIssuedCertificates()
-	assert.Len(cs, 2)
+			cs := manager.ListIssuedCertificates()
+			assert.Len(cs, 2)
 
-	for i, c := range cs {
-		match := false
-		for _, ec := range expectedCertificates {
-			if c.GetCommonName() == ec.GetCommonName() {
-				match = true
-				assert.Equal(ec, c)
-				break
-			}
-		}
+			for i, c := range cs {
+				match := false
+				for _, ec := range expectedCertificates {
+					if c.GetCommonName() == ec.GetCommonName() {
+						match = true
+						assert.Equal(ec, c)
+						break
+					}
+				}
 
-		if !match {
-			t.Fatalf("Certificate #%v %v does not exist", i, c.GetCommonName())
-		}
+				if !match {
+					t.Fatalf("Certificate #%v %v does not exist", i, c.GetCommonName())
+				}
+			}
+		})
 	}
 }
 
@@ -116,9 +161,10 @@ func TestIssueCertificate(t *testing.T) {
 
 	t.Run("single key issuer", func(t *testing.T) {
 		cm := &Manager{
+			cache: NewMemoryCache(),
 			// The root certificate signing all newly issued certificates
-			signingIssuer:               &issuer{ID: "id1", Issuer: &fakeIssuer{id: "id1"}, CertificateAuthority: pem.RootCertificate("id1"), TrustDomain: "fake1.domain.com"},
-			validatingIssuer:            &issuer{ID: "id1", Issuer: &fakeIssuer{id: "id1"}, CertificateAuthority: pem.RootCertificate("id1"), TrustDomain: "fake2.domain.com"},
+			signingIssuer:               &issuer{ID: "id1", Provider: &fakeProvider{id: "id1"}, CertificateAuthority: pem.RootCertificate("id1"), TrustDomain: "fake1.domain.com"},
+			validatingIssuer:            &issuer{ID: "id1", Provider: &fakeProvider{id: "id1"}, CertificateAuthority: pem.RootCertificate("id1"), TrustDomain: "fake2.domain.com"},
 			serviceCertValidityDuration: time.Hour,
 		}
 		// single signingIssuer, not cached
@@ -138,8 +184,8 @@ func TestIssueCertificate(t *testing.T) {
 
 		// single key issuer, old version cached
 		// TODO: could use informer logic to test mrc updates instead of just manually making changes.
-		cm.signingIssuer = &issuer{ID: "id2", Issuer: &fakeIssuer{id: "id2"}, CertificateAuthority: pem.RootCertificate("id2"), TrustDomain: "fake2.domain.com"}
-		cm.validatingIssuer = &issuer{ID: "id2", Issuer: &fakeIssuer{id: "id2"}, CertificateAuthority: pem.RootCertificate("id2")}
+		cm.signingIssuer = &issuer{ID: "id2", Provider: &fakeProvider{id: "id2"}, CertificateAuthority: pem.RootCertificate("id2"), TrustDomain: "fake2.domain.com"}
+		cm.validatingIssuer = &issuer{ID: "id2", Provider: &fakeProvider{id: "id2"}, CertificateAuthority: pem.RootCertificate("id2")}
 
 		cert3, err := cm.IssueCertificate(cnPrefix)
 		assert.Equal(CommonName("fake-cert-cn.fake2.domain.com"), cert3.GetCommonName())
@@ -154,9 +200,10 @@ func TestIssueCertificate(t *testing.T) {
 
 	t.Run("2 issuers", func(t *testing.T) {
 		cm := &Manager{
+			cache: NewMemoryCache(),
 			// The root certificate signing all newly issued certificates
-			signingIssuer:               &issuer{ID: "id1", Issuer: &fakeIssuer{id: "id1"}, CertificateAuthority: pem.RootCertificate("id1"), TrustDomain: "fake1.domain.com"},
-			validatingIssuer:            &issuer{ID: "id2", Issuer: &fakeIssuer{id: "id2"}, CertificateAuthority: pem.RootCertificate("id2"), TrustDomain: "fake2.domain.com"},
+			signingIssuer:               &issuer{ID: "id1", Provider: &fakeProvider{id: "id1"}, CertificateAuthority: pem.RootCertificate("id1"), TrustDomain: "fake1.domain.com"},
+			validatingIssuer:            &issuer{ID: "id2", Provider: &fakeProvider{id: "id2"}, CertificateAuthority: pem.RootCertificate("id2"), TrustDomain: "fake2.domain.com"},
 			serviceCertValidityDuration: time.Hour,
 		}
 
@@ -187,7 +234,7 @@ func TestIssueCertificate(t *testing.T) {
 		assert.Equal(CommonName("fake-cert-cn.fake1.domain.com"), cert1.GetCommonName())
 
 		// cached, but signingIssuer is old
-		cm.signingIssuer = &issuer{ID: "id2", Issuer: &fakeIssuer{id: "id2"}, CertificateAuthority: pem.RootCertificate("id2"), TrustDomain: "fake2.domain.com"}
+		cm.signingIssuer = &issuer{ID: "id2", Provider: &fakeProvider{id: "id2"}, CertificateAuthority: pem.RootCertificate("id2"), TrustDomain: "fake2.domain.com"}
 		cert4, err := cm.IssueCertificate(cnPrefix)
 		assert.NoError(err)
 		assert.NotEqual(cert3, cert4)
@@ -198,7 +245,7 @@ func TestIssueCertificate(t *testing.T) {
 		assert.Equal(CommonName("fake-cert-cn.fake2.domain.com"), cert4.GetCommonName())
 
 		// cached, but validatingIssuer is old
-		cm.validatingIssuer = &issuer{ID: "id3", Issuer: &fakeIssuer{id: "id3"}, CertificateAuthority: pem.RootCertificate("id3"), TrustDomain: "fake3.domain.com"}
+		cm.validatingIssuer = &issuer{ID: "id3", Provider: &fakeProvider{id: "id3"}, CertificateAuthority: pem.RootCertificate("id3"), TrustDomain: "fake3.domain.com"}
 		cert5, err := cm.IssueCertificate(cnPrefix)
 		assert.NoError(err)
 		assert.NotEqual(cert4, cert5)
@@ -211,9 +258,10 @@ func TestIssueCertificate(t *testing.T) {
 
 	t.Run("bad issuers", func(t *testing.T) {
 		cm := &Manager{
+			cache: NewMemoryCache(),
 			// The root certificate signing all newly issued certificates
-			signingIssuer:               &issuer{ID: "id1", Issuer: &fakeIssuer{id: "id1", err: true}, CertificateAuthority: pem.RootCertificate("id1")},
-			validatingIssuer:            &issuer{ID: "id2", Issuer: &fakeIssuer{id: "id2", err: true}, CertificateAuthority: pem.RootCertificate("id2")},
+			signingIssuer:               &issuer{ID: "id1", Provider: &fakeProvider{id: "id1", err: true}, CertificateAuthority: pem.RootCertificate("id1")},
+			validatingIssuer:            &issuer{ID: "id2", Provider: &fakeProvider{id: "id2", err: true}, CertificateAuthority: pem.RootCertificate("id2")},
 			serviceCertValidityDuration: time.Hour,
 		}
 
@@ -223,7 +271,7 @@ func TestIssueCertificate(t *testing.T) {
 		assert.EqualError(err, "id1 failed")
 
 		// bad validatingIssuer (should still succeed)
-		cm.signingIssuer = &issuer{ID: "id3", Issuer: &fakeIssuer{id: "id3"}, CertificateAuthority: pem.RootCertificate("id3")}
+		cm.signingIssuer = &issuer{ID: "id3", Provider: &fakeProvider{id: "id3"}, CertificateAuthority: pem.RootCertificate("id3")}
 		cert, err = cm.IssueCertificate(cnPrefix)
 		assert.NoError(err)
 		assert.Equal(cert.signingIssuerID, "id3")
@@ -238,9 +286,86 @@ func TestIssueCertificate(t *testing.T) {
 		assert.NotNil(cert)
 
 		// bad signing cert on an existing cached cert, because the signingIssuer is new
-		cm.signingIssuer = &issuer{ID: "id1", Issuer: &fakeIssuer{id: "id1", err: true}, CertificateAuthority: pem.RootCertificate("id1")}
+		cm.signingIssuer = &issuer{ID: "id1", Provider: &fakeProvider{id: "id1", err: true}, CertificateAuthority: pem.RootCertificate("id1")}
 		cert, err = cm.IssueCertificate(cnPrefix)
 		assert.EqualError(err, "id1 failed")
 		assert.Nil(cert)
 	})
 }
+
+func TestIssueCertificateForCSR(t *testing.T) {
+	assert := tassert.New(t)
+
+	cm := &Manager{
+		cache:                       NewMemoryCache(),
+		signingIssuer:               &issuer{ID: "id1", Provider: &fakeProvider{id: "id1"}, CertificateAuthority: pem.RootCertificate("id1")},
+		validatingIssuer:            &issuer{ID: "id1", Provider: &fakeProvider{id: "id1"}, CertificateAuthority: pem.RootCertificate("id1")},
+		serviceCertValidityDuration: time.Hour,
+	}
+
+	csr, _, err := newCSR(CommonName("proxy-cn"))
+	assert.NoError(err)
+
+	cert, err := cm.IssueCertificateForCSR(context.Background(), csr)
+	assert.NoError(err)
+	assert.Equal(CommonName("proxy-cn"), cert.GetCommonName())
+	assert.Nil(cert.GetPrivateKey())
+
+	// A second call for the same CSR CN should be served from the cache.
+	cert2, err := cm.IssueCertificateForCSR(context.Background(), csr)
+	assert.NoError(err)
+	assert.Equal(cert, cert2)
+
+	_, err = cm.IssueCertificateForCSR(context.Background(), nil)
+	assert.Error(err)
+}
+
+func TestIssueCertificateForCSRRejectsBadSignature(t *testing.T) {
+	assert := tassert.New(t)
+
+	cm := &Manager{
+		cache:                       NewMemoryCache(),
+		signingIssuer:               &issuer{ID: "id1", Provider: &fakeProvider{id: "id1"}, CertificateAuthority: pem.RootCertificate("id1")},
+		validatingIssuer:            &issuer{ID: "id1", Provider: &fakeProvider{id: "id1"}, CertificateAuthority: pem.RootCertificate("id1")},
+		serviceCertValidityDuration: time.Hour,
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	// A CSR the caller never actually signed -- i.e. no proof of possession
+	// of the private key behind the public key it's asking to have certified.
+	csr := &x509.CertificateRequest{
+		Subject:   pkix.Name{CommonName: "proxy-cn"},
+		PublicKey: key.Public(),
+	}
+
+	_, err = cm.IssueCertificateForCSR(context.Background(), csr)
+	assert.ErrorIs(err, errInvalidCSRSignature)
+}
+
+func TestRenewContext(t *testing.T) {
+	assert := tassert.New(t)
+
+	cm := &Manager{
+		cache:                       NewMemoryCache(),
+		signingIssuer:               &issuer{ID: "id1", Provider: &fakeProvider{id: "id1"}, CertificateAuthority: pem.RootCertificate("id1")},
+		validatingIssuer:            &issuer{ID: "id1", Provider: &fakeProvider{id: "id1"}, CertificateAuthority: pem.RootCertificate("id1")},
+		serviceCertValidityDuration: time.Hour,
+	}
+
+	peerCert, err := cm.IssueCertificate("proxy-cn")
+	assert.NoError(err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	renewed, err := cm.RenewContext(context.Background(), peerCert, key.Public())
+	assert.NoError(err)
+	assert.NotEqual(peerCert, renewed)
+	assert.Nil(renewed.GetPrivateKey())
+	assert.Equal(peerCert.GetCommonName(), renewed.GetCommonName())
+
+	_, err = cm.RenewContext(context.Background(), nil, key.Public())
+	assert.Error(err)
+}
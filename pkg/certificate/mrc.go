@@ -0,0 +1,11 @@
+package certificate
+
+// MRCClient sources the CA provider configuration from MeshRootCertificate
+// custom resources: which registered Provider to construct, its
+// provider-specific options, and the trust domain certificates issued
+// against it should be scoped to.
+type MRCClient interface {
+	// GetCertIssuerConfig returns the name of the registered Provider backing
+	// issuance (see RegisterProvider), that provider's options, and the trust domain.
+	GetCertIssuerConfig() (kind string, options map[string]string, trustDomain string, err error)
+}
@@ -0,0 +1,18 @@
+package certificate
+
+import "time"
+
+// issueOptions carries the optional parameters for certificate issuance.
+type issueOptions struct {
+	validityPeriod time.Duration
+}
+
+// IssueOption is a functional option used to customize certificate issuance.
+type IssueOption func(*issueOptions)
+
+// WithValidityPeriod overrides the default validity period configured on the Manager.
+func WithValidityPeriod(validityPeriod time.Duration) IssueOption {
+	return func(o *issueOptions) {
+		o.validityPeriod = validityPeriod
+	}
+}
@@ -0,0 +1,19 @@
+// Package pem defines the PEM-encoded byte types passed around the certificate
+// package so that call sites don't confuse a raw certificate, a private key,
+// and a CA bundle, which are otherwise all just []byte.
+package pem
+
+// RootCertificate is a PEM-encoded CA certificate (or bundle of CA certificates).
+type RootCertificate []byte
+
+// Certificate is a PEM-encoded leaf certificate, optionally followed by the
+// intermediate chain.
+type Certificate []byte
+
+// PrivateKey is a PEM-encoded private key.
+type PrivateKey []byte
+
+// EncryptedPrivateKey is a private key wrapped with a passphrase-derived key
+// for storage at rest, e.g. the tresor provider's CA key in its backing
+// Secret. It is opaque outside of the provider that produced it.
+type EncryptedPrivateKey []byte
@@ -0,0 +1,71 @@
+package certificate
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
+)
+
+// Provider is implemented by pluggable CA backends, such as the in-tree
+// Tresor (self-signed) provider, modeled on Consul Connect's CA provider
+// interface. Operators can register additional backends -- e.g. Hashicorp
+// Vault, cert-manager, ACME, or AWS Private CA -- via RegisterProvider
+// without forking OSM.
+type Provider interface {
+	// Bootstrap prepares the provider for use, returning its root certificate
+	// and, if the provider signs through an intermediate, the certificate chain
+	// up to that intermediate.
+	Bootstrap(ctx context.Context) (rootPEM pem.RootCertificate, chainPEM pem.RootCertificate, err error)
+
+	// Sign signs csr and returns the resulting certificate. It is the only way
+	// to obtain a signed certificate from a Provider -- the Provider never
+	// generates or holds the leaf's private key; callers generate it
+	// themselves and submit a CSR.
+	Sign(ctx context.Context, csr *x509.CertificateRequest, validityPeriod time.Duration) (*Certificate, error)
+
+	// ActiveIntermediate returns the CA certificate currently used to sign leaf certificates.
+	ActiveIntermediate() pem.RootCertificate
+
+	// GenerateIntermediate rotates in a new signing intermediate.
+	GenerateIntermediate(ctx context.Context) error
+
+	// Cleanup releases any resources (connections, temp credentials, background
+	// refreshers) held by the provider.
+	Cleanup(ctx context.Context) error
+}
+
+// ProviderFactory constructs a Provider from options sourced from a
+// MeshRootCertificate CR.
+type ProviderFactory func(options map[string]string) (Provider, error)
+
+var providerRegistry = struct {
+	mu        sync.RWMutex
+	factories map[string]ProviderFactory
+}{factories: make(map[string]ProviderFactory)}
+
+// RegisterProvider registers a ProviderFactory under name so that a
+// MeshRootCertificate CR can select it by name. The in-tree tresor provider
+// registers itself this way via an init function; it is exported so
+// downstream operators can drop in their own providers -- for backends such
+// as Vault, cert-manager, ACME, or AWS Private CA -- without forking OSM.
+func RegisterProvider(name string, factory ProviderFactory) {
+	providerRegistry.mu.Lock()
+	defer providerRegistry.mu.Unlock()
+	providerRegistry.factories[name] = factory
+}
+
+// GetProviderFactory looks up a previously registered ProviderFactory by name.
+func GetProviderFactory(name string) (ProviderFactory, error) {
+	providerRegistry.mu.RLock()
+	defer providerRegistry.mu.RUnlock()
+
+	factory, ok := providerRegistry.factories[name]
+	if !ok {
+		return nil, fmt.Errorf("no certificate provider registered under name %q", name)
+	}
+	return factory, nil
+}
@@ -0,0 +1,126 @@
+package tresor
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/openservicemesh/osm/pkg/certificate/pem"
+)
+
+// ErrWrongPassphrase is returned by DecryptPrivateKey when the passphrase
+// doesn't open the key, either because it's wrong or the key at rest is
+// corrupt. Callers (e.g. Provider.Bootstrap) use it to tell "try the previous
+// passphrase" apart from an unrelated failure.
+var ErrWrongPassphrase = errors.New("tresor: wrong passphrase or corrupt key")
+
+const (
+	pbkdf2Iterations = 100_000
+	pbkdf2KeyLen     = 32 // AES-256
+	saltLen          = 16
+)
+
+// EncryptPrivateKey wraps key for storage at rest, deriving an AES-256 key
+// from passphrase with a freshly generated salt and sealing the PKCS#8
+// encoding with AES-GCM. The salt and nonce are stored alongside the
+// ciphertext so DecryptPrivateKey needs nothing but the passphrase.
+func EncryptPrivateKey(key *ecdsa.PrivateKey, passphrase string) (pem.EncryptedPrivateKey, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("tresor: error marshaling CA key: %w", err)
+	}
+
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(salt)+len(nonce)+len(der)+gcm.Overhead())
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	out = gcm.Seal(out, nonce, der, nil)
+
+	return pem.EncryptedPrivateKey(out), nil
+}
+
+// DecryptPrivateKey reverses EncryptPrivateKey. It returns ErrWrongPassphrase,
+// wrapped with the underlying cause, if passphrase does not open enc.
+func DecryptPrivateKey(enc pem.EncryptedPrivateKey, passphrase string) (*ecdsa.PrivateKey, error) {
+	gcm, nonce, ciphertext, err := gcmFor(enc, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrWrongPassphrase, err)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrWrongPassphrase, err)
+	}
+
+	ecKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("tresor: decrypted CA key is a %T, not ECDSA", key)
+	}
+
+	return ecKey, nil
+}
+
+// RewrapPrivateKey decrypts enc with oldPassphrase and re-encrypts the result
+// with newPassphrase, used to migrate a key at rest across a passphrase
+// rotation.
+func RewrapPrivateKey(enc pem.EncryptedPrivateKey, oldPassphrase, newPassphrase string) (pem.EncryptedPrivateKey, error) {
+	key, err := DecryptPrivateKey(enc, oldPassphrase)
+	if err != nil {
+		return nil, err
+	}
+	return EncryptPrivateKey(key, newPassphrase)
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+	derivedKey := pbkdf2.Key([]byte(passphrase), salt, pbkdf2Iterations, pbkdf2KeyLen, sha256.New)
+
+	block, err := aes.NewCipher(derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func gcmFor(enc pem.EncryptedPrivateKey, passphrase string) (gcm cipher.AEAD, nonce, ciphertext []byte, err error) {
+	if len(enc) < saltLen {
+		return nil, nil, nil, ErrWrongPassphrase
+	}
+	salt, rest := enc[:saltLen], enc[saltLen:]
+
+	gcm, err = newGCM(passphrase, salt)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if len(rest) < gcm.NonceSize() {
+		return nil, nil, nil, ErrWrongPassphrase
+	}
+
+	return gcm, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], nil
+}
@@ -0,0 +1,220 @@
+// Package tresor implements a self-signed certificate.Provider: it generates
+// its own root CA rather than delegating to an external CA backend like Vault
+// or cert-manager.
+package tresor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+	ospem "github.com/openservicemesh/osm/pkg/certificate/pem"
+)
+
+const providerName = "tresor"
+
+// OptionEncryptedKey names the option a caller that has loaded a previously
+// persisted, passphrase-encrypted CA key (e.g. from the osm-ca-bundle Secret)
+// passes it in under, base64-encoded.
+const OptionEncryptedKey = "encryptedKey"
+
+func init() {
+	certificate.RegisterProvider(providerName, newProvider)
+}
+
+// Provider is a self-signed certificate.Provider. Its root CA key can
+// optionally be persisted encrypted at rest -- see EncryptPrivateKey -- with
+// the passphrase(s) sourced from OptionKeyPassphrase/OptionKeyPassphrasePrev.
+type Provider struct {
+	mu sync.Mutex
+
+	passphrase     string
+	passphrasePrev string
+
+	encryptedKey ospem.EncryptedPrivateKey
+
+	caPrivKey *ecdsa.PrivateKey
+	caCertDER []byte
+	rootPEM   ospem.RootCertificate
+
+	serial int64
+}
+
+func newProvider(options map[string]string) (certificate.Provider, error) {
+	p := &Provider{
+		passphrase:     options[certificate.OptionKeyPassphrase],
+		passphrasePrev: options[certificate.OptionKeyPassphrasePrev],
+	}
+
+	if encoded := options[OptionEncryptedKey]; encoded != "" {
+		raw, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("tresor: invalid %s option: %w", OptionEncryptedKey, err)
+		}
+		p.encryptedKey = ospem.EncryptedPrivateKey(raw)
+	}
+
+	return p, nil
+}
+
+// Bootstrap generates (or, if an encrypted key was supplied, loads) the root
+// CA key and self-signs a root certificate for it.
+func (p *Provider) Bootstrap(_ context.Context) (ospem.RootCertificate, ospem.RootCertificate, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key, err := p.loadOrGenerateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certDER, err := selfSignRootCert(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p.caPrivKey = key
+	p.caCertDER = certDER
+	p.rootPEM = ospem.RootCertificate(encodeCertPEM(certDER))
+
+	if p.passphrase != "" {
+		enc, err := EncryptPrivateKey(key, p.passphrase)
+		if err != nil {
+			return nil, nil, fmt.Errorf("tresor: error encrypting CA key: %w", err)
+		}
+		p.encryptedKey = enc
+	}
+
+	return p.rootPEM, nil, nil
+}
+
+// loadOrGenerateKey decrypts p.encryptedKey if one was supplied, falling back
+// to p.passphrasePrev -- and re-wrapping with p.passphrase on success -- if
+// p.passphrase can't open it, since that's the expected state right after a
+// passphrase rotation. With no encrypted key on hand it generates a fresh one.
+func (p *Provider) loadOrGenerateKey() (*ecdsa.PrivateKey, error) {
+	if p.encryptedKey == nil {
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+
+	key, err := DecryptPrivateKey(p.encryptedKey, p.passphrase)
+	if err == nil {
+		return key, nil
+	}
+	if !errors.Is(err, ErrWrongPassphrase) || p.passphrasePrev == "" {
+		return nil, err
+	}
+
+	key, err = DecryptPrivateKey(p.encryptedKey, p.passphrasePrev)
+	if err != nil {
+		return nil, err
+	}
+
+	rewrapped, err := EncryptPrivateKey(key, p.passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("tresor: error re-wrapping CA key: %w", err)
+	}
+	p.encryptedKey = rewrapped
+
+	return key, nil
+}
+
+// EncryptedKey returns the CA private key as currently persisted at rest,
+// for a caller (e.g. the MRC controller) to write back to the osm-ca-bundle
+// Secret -- notably after loadOrGenerateKey has rewrapped it with the current
+// passphrase during a rotation.
+func (p *Provider) EncryptedKey() ospem.EncryptedPrivateKey {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.encryptedKey
+}
+
+// Sign signs csr directly against the self-signed root CA.
+func (p *Provider) Sign(_ context.Context, csr *x509.CertificateRequest, validityPeriod time.Duration) (*certificate.Certificate, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	caCert, err := x509.ParseCertificate(p.caCertDER)
+	if err != nil {
+		return nil, fmt.Errorf("tresor: error parsing CA certificate: %w", err)
+	}
+
+	serial := p.nextSerial()
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:       serial,
+		Subject:            csr.Subject,
+		NotBefore:          now,
+		NotAfter:           now.Add(validityPeriod),
+		KeyUsage:           x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:        []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:           csr.DNSNames,
+		SignatureAlgorithm: x509.ECDSAWithSHA256,
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, caCert, csr.PublicKey, p.caPrivKey)
+	if err != nil {
+		return nil, fmt.Errorf("tresor: error signing certificate for %s: %w", csr.Subject.CommonName, err)
+	}
+
+	return &certificate.Certificate{
+		CommonName:   certificate.CommonName(csr.Subject.CommonName),
+		SerialNumber: certificate.SerialNumber(serial.String()),
+		CertChain:    ospem.Certificate(encodeCertPEM(certDER)),
+		Expiration:   template.NotAfter,
+	}, nil
+}
+
+func (p *Provider) nextSerial() *big.Int {
+	p.serial++
+	return big.NewInt(p.serial)
+}
+
+// ActiveIntermediate returns the root certificate, since tresor signs leaf
+// certificates directly against it rather than through an intermediate.
+func (p *Provider) ActiveIntermediate() ospem.RootCertificate {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.rootPEM
+}
+
+// GenerateIntermediate is a no-op: tresor has no intermediate to rotate in.
+func (p *Provider) GenerateIntermediate(_ context.Context) error {
+	return nil
+}
+
+// Cleanup is a no-op: tresor holds no external resources.
+func (p *Provider) Cleanup(_ context.Context) error {
+	return nil
+}
+
+func selfSignRootCert(key *ecdsa.PrivateKey) ([]byte, error) {
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "osm-ca"},
+		NotBefore:             now,
+		NotAfter:              now.Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SignatureAlgorithm:    x509.ECDSAWithSHA256,
+	}
+
+	return x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+}
+
+func encodeCertPEM(der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
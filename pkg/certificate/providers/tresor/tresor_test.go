@@ -0,0 +1,124 @@
+package tresor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/certificate"
+)
+
+func TestEncryptDecryptPrivateKey(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	enc, err := EncryptPrivateKey(key, "correct horse battery staple")
+	assert.NoError(err)
+	assert.NotEmpty(enc)
+
+	decrypted, err := DecryptPrivateKey(enc, "correct horse battery staple")
+	assert.NoError(err)
+	assert.Equal(key.D, decrypted.D)
+
+	_, err = DecryptPrivateKey(enc, "wrong passphrase")
+	assert.ErrorIs(err, ErrWrongPassphrase)
+}
+
+func TestRewrapPrivateKey(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	enc, err := EncryptPrivateKey(key, "old-passphrase")
+	assert.NoError(err)
+
+	rewrapped, err := RewrapPrivateKey(enc, "old-passphrase", "new-passphrase")
+	assert.NoError(err)
+
+	_, err = DecryptPrivateKey(rewrapped, "old-passphrase")
+	assert.ErrorIs(err, ErrWrongPassphrase)
+
+	decrypted, err := DecryptPrivateKey(rewrapped, "new-passphrase")
+	assert.NoError(err)
+	assert.Equal(key.D, decrypted.D)
+}
+
+func TestBootstrapLoadsEncryptedKeyAfterPassphraseRotation(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	enc, err := EncryptPrivateKey(key, "old-passphrase")
+	assert.NoError(err)
+
+	p := &Provider{
+		passphrase:     "new-passphrase",
+		passphrasePrev: "old-passphrase",
+		encryptedKey:   enc,
+	}
+
+	rootPEM, _, err := p.Bootstrap(context.Background())
+	assert.NoError(err)
+	assert.NotEmpty(rootPEM)
+	// The key at rest should now be wrapped with the current passphrase.
+	assert.NotEqual(pem2str(enc), pem2str(p.EncryptedKey()))
+
+	_, err = DecryptPrivateKey(p.EncryptedKey(), "new-passphrase")
+	assert.NoError(err)
+}
+
+func TestBootstrapWrongPassphraseDoesNotPanic(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	enc, err := EncryptPrivateKey(key, "old-passphrase")
+	assert.NoError(err)
+
+	p := &Provider{
+		passphrase:   "some-other-passphrase",
+		encryptedKey: enc,
+	}
+
+	_, _, err = p.Bootstrap(context.Background())
+	assert.ErrorIs(err, ErrWrongPassphrase)
+}
+
+func TestSignIssuesCertificateForCSR(t *testing.T) {
+	assert := assert.New(t)
+
+	provider, err := newProvider(nil)
+	assert.NoError(err)
+
+	_, _, err = provider.Bootstrap(context.Background())
+	assert.NoError(err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(err)
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "proxy.cluster.local"}}, key)
+	assert.NoError(err)
+	csr, err := x509.ParseCertificateRequest(der)
+	assert.NoError(err)
+
+	cert, err := provider.Sign(context.Background(), csr, 0)
+	assert.NoError(err)
+	assert.Equal(certificate.CommonName("proxy.cluster.local"), cert.GetCommonName())
+	assert.NotEmpty(cert.GetCertificateChain())
+}
+
+func pem2str(b []byte) string {
+	return base64.StdEncoding.EncodeToString(b)
+}
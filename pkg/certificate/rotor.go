@@ -0,0 +1,217 @@
+package certificate
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+)
+
+// RotationPolicy configures how and when a Manager proactively rotates
+// certificates ahead of expiry, instead of waiting for ShouldRotate to flip
+// at the exact expiry instant.
+type RotationPolicy struct {
+	// RenewBefore is the fraction, in (0, 1], of a certificate's validity
+	// period that must elapse before it's rotated -- e.g. 2.0/3.0 rotates
+	// once two thirds of the validity window has passed, mirroring kubelet's
+	// certificate manager. Defaults to 2.0/3.0 if unset or out of range.
+	RenewBefore float64
+
+	// Jitter is the fraction, in [0, 1], by which a scheduled rotation is
+	// randomly shifted earlier or later, so that certificates issued around
+	// the same time -- e.g. thousands of sidecars at mesh bootstrap -- don't
+	// all rotate in the same instant.
+	Jitter float64
+
+	// MinBackoff and MaxBackoff bound the exponential backoff applied between
+	// retries after a failed rotation. Default to 1s and 1m if unset.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// HealthCheck, if set, runs immediately before every scheduled rotation
+	// attempt. An error is logged but does not block the rotation -- it
+	// exists so a caller can detect conditions, like an MRC flagging the
+	// signing CA as compromised, that make rotating right now especially
+	// important, without the Manager needing to know about MRCs itself.
+	HealthCheck func(*Certificate) error
+}
+
+func (p RotationPolicy) renewBefore() float64 {
+	if p.RenewBefore <= 0 || p.RenewBefore > 1 {
+		return 2.0 / 3.0
+	}
+	return p.RenewBefore
+}
+
+func (p RotationPolicy) minBackoff() time.Duration {
+	if p.MinBackoff <= 0 {
+		return time.Second
+	}
+	return p.MinBackoff
+}
+
+func (p RotationPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff <= 0 {
+		return time.Minute
+	}
+	return p.MaxBackoff
+}
+
+// rotationState tracks the in-flight timer, consecutive-failure count, and
+// last-known certificate backing a single cnPrefix's scheduled rotation. cert
+// is tracked here rather than re-read from the cache because runRotation
+// releases the cached certificate before reissuing it, and a failed reissue
+// must still have a certificate to retry and back off against.
+type rotationState struct {
+	timer    *time.Timer
+	failures int
+	cert     *Certificate
+}
+
+// Start begins proactively rotating certificates per policy until stop is
+// closed. Every certificate currently in the cache, and every certificate
+// issued afterwards, gets its own timer scheduled ahead of its expiry --
+// there is no periodic scan.
+func (m *Manager) Start(policy RotationPolicy, stop <-chan struct{}) {
+	m.rotationMu.Lock()
+	m.rotationPolicy = &policy
+	if m.rotationStates == nil {
+		m.rotationStates = make(map[string]*rotationState)
+	}
+	m.rotationMu.Unlock()
+
+	certs, err := m.cache.List(context.Background())
+	if err != nil {
+		log.Printf("certificate: error listing cache to schedule rotation: %s", err)
+	}
+	for _, cert := range certs {
+		m.scheduleRotation(cert.cnPrefix, cert, 0)
+	}
+
+	go func() {
+		<-stop
+		m.stopRotation()
+	}()
+}
+
+func (m *Manager) stopRotation() {
+	m.rotationMu.Lock()
+	defer m.rotationMu.Unlock()
+
+	for _, state := range m.rotationStates {
+		state.timer.Stop()
+	}
+	m.rotationPolicy = nil
+}
+
+// scheduleRotation (re)schedules cnPrefix's rotation timer. With failures == 0
+// it's scheduled per the RotationPolicy's RenewBefore/Jitter against cert's
+// validity window; with failures > 0 it's scheduled per the policy's
+// exponential backoff instead, so a provider outage doesn't busy-loop.
+func (m *Manager) scheduleRotation(cnPrefix string, cert *Certificate, failures int) {
+	m.rotationMu.Lock()
+	defer m.rotationMu.Unlock()
+
+	policy := m.rotationPolicy
+	if policy == nil {
+		return
+	}
+
+	delay := rotationDelay(*policy, cert, failures)
+
+	state, ok := m.rotationStates[cnPrefix]
+	if !ok {
+		state = &rotationState{}
+		m.rotationStates[cnPrefix] = state
+	} else if state.timer != nil {
+		state.timer.Stop()
+	}
+	state.failures = failures
+	state.cert = cert
+	state.timer = time.AfterFunc(delay, func() { m.runRotation(cnPrefix) })
+}
+
+// runRotation is the time.AfterFunc callback scheduled for cnPrefix: it
+// reissues the certificate and announces the outcome over the message broker.
+// On success it does not reschedule itself -- issueCertificate already calls
+// scheduleRotation for every certificate it issues, including this one -- but
+// on failure it must reschedule explicitly, with the pre-release certificate
+// carried on state, since the cache no longer holds one to read back.
+func (m *Manager) runRotation(cnPrefix string) {
+	m.rotationMu.Lock()
+	policy := m.rotationPolicy
+	state := m.rotationStates[cnPrefix]
+	var cert *Certificate
+	if state != nil {
+		cert = state.cert
+	}
+	m.rotationMu.Unlock()
+	if policy == nil || state == nil || cert == nil {
+		return
+	}
+
+	if policy.HealthCheck != nil {
+		if err := policy.HealthCheck(cert); err != nil {
+			log.Printf("certificate: health check flagged %s for rotation: %s", cnPrefix, err)
+		}
+	}
+
+	m.ReleaseCertificate(cnPrefix)
+	_, err := m.IssueCertificate(cnPrefix)
+	if err != nil {
+		failures := state.failures + 1
+		log.Printf("certificate: error rotating %s (attempt %d): %s", cnPrefix, failures, err)
+		m.scheduleRotation(cnPrefix, cert, failures)
+		if m.msgBroker != nil {
+			m.msgBroker.GetCertPubSub().Pub(announcements.CertificateRotationFailed.String(), announcements.CertificateRotationFailed.String())
+		}
+		return
+	}
+
+	if m.msgBroker != nil {
+		m.msgBroker.GetCertPubSub().Pub(announcements.CertificateRotated.String(), announcements.CertificateRotated.String())
+	}
+}
+
+// rotationDelay computes how long to wait before cert's next rotation
+// attempt. Failures take precedence: they schedule exponential backoff
+// regardless of how close cert is to expiry, since a cert a failing provider
+// can't reissue is still the best one available.
+func rotationDelay(policy RotationPolicy, cert *Certificate, failures int) time.Duration {
+	if failures > 0 {
+		return backoffDelay(policy, failures)
+	}
+
+	validity := cert.Expiration.Sub(cert.IssuedAt)
+	renewAt := cert.IssuedAt.Add(time.Duration(float64(validity) * policy.renewBefore()))
+	delay := time.Until(renewAt)
+
+	if policy.Jitter > 0 {
+		jitterAmt := time.Duration((rand.Float64()*2 - 1) * policy.Jitter * float64(delay)) //nolint:gosec // timing jitter, not security sensitive
+		delay += jitterAmt
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return delay
+}
+
+// backoffDelay returns MinBackoff after the first failure, doubled once per
+// further failure, capped at MaxBackoff.
+func backoffDelay(policy RotationPolicy, failures int) time.Duration {
+	if failures < 1 {
+		failures = 1
+	}
+	if failures > 32 { // avoid overflowing the shift below; MaxBackoff caps it anyway
+		failures = 32
+	}
+
+	minBackoff, maxBackoff := policy.minBackoff(), policy.maxBackoff()
+	delay := minBackoff * time.Duration(uint64(1)<<uint(failures-1))
+	if delay <= 0 || delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
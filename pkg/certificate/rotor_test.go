@@ -0,0 +1,117 @@
+package certificate
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	tassert "github.com/stretchr/testify/assert"
+
+	"github.com/openservicemesh/osm/pkg/announcements"
+	"github.com/openservicemesh/osm/pkg/messaging"
+)
+
+func TestRotationFiresBeforeExpiry(t *testing.T) {
+	assert := tassert.New(t)
+
+	validityPeriod := 200 * time.Millisecond
+	stop := make(chan struct{})
+	defer close(stop)
+	msgBroker := messaging.NewBroker(stop)
+
+	cm, err := NewManager(&fakeMRCClient{}, NewMemoryCache(), validityPeriod, msgBroker)
+	assert.NoError(err)
+
+	cm.Start(RotationPolicy{RenewBefore: 0.5}, stop) // rotate halfway through validity
+
+	rotated := msgBroker.GetCertPubSub().Sub(announcements.CertificateRotated.String())
+
+	cert, err := cm.IssueCertificate("foo")
+	assert.NoError(err)
+
+	select {
+	case <-rotated:
+		assert.True(time.Now().Before(cert.GetExpiration()), "rotation should fire before the original certificate expires")
+	case <-time.After(validityPeriod):
+		t.Fatal("certificate was not rotated before its original expiry")
+	}
+}
+
+func TestJitterSpreadsScheduledRotations(t *testing.T) {
+	assert := tassert.New(t)
+
+	policy := RotationPolicy{RenewBefore: 2.0 / 3.0, Jitter: 0.5}
+	issuedAt := time.Now()
+	cert := &Certificate{IssuedAt: issuedAt, Expiration: issuedAt.Add(time.Hour)}
+
+	delays := make(map[time.Duration]bool)
+	var min, max time.Duration
+	for i := 0; i < 100; i++ {
+		d := rotationDelay(policy, cert, 0)
+		delays[d] = true
+		if min == 0 || d < min {
+			min = d
+		}
+		if d > max {
+			max = d
+		}
+	}
+
+	assert.Greater(len(delays), 1, "jitter should produce varying delays across 100 schedules")
+	assert.Greater(max-min, time.Duration(0), "jitter should spread schedules over a non-zero window")
+}
+
+func TestBackoffDelayGrowsAndCaps(t *testing.T) {
+	assert := tassert.New(t)
+
+	policy := RotationPolicy{MinBackoff: 10 * time.Millisecond, MaxBackoff: 200 * time.Millisecond}
+
+	prev := time.Duration(0)
+	for failures := 1; failures <= 10; failures++ {
+		d := backoffDelay(policy, failures)
+		assert.GreaterOrEqual(d, prev, fmt.Sprintf("backoff should not shrink between failure %d and %d", failures-1, failures))
+		assert.LessOrEqual(d, policy.MaxBackoff, "backoff should never exceed MaxBackoff")
+		prev = d
+	}
+	// Enough failures should saturate at MaxBackoff.
+	assert.Equal(policy.MaxBackoff, backoffDelay(policy, 10))
+}
+
+func TestRotorBacksOffOnRepeatedFailure(t *testing.T) {
+	assert := tassert.New(t)
+
+	stop := make(chan struct{})
+	defer close(stop)
+	msgBroker := messaging.NewBroker(stop)
+
+	cm := &Manager{
+		cache:                       NewMemoryCache(),
+		signingIssuer:               &issuer{ID: "id1", Provider: &fakeProvider{id: "id1", err: true}, CertificateAuthority: []byte("id1")},
+		validatingIssuer:            &issuer{ID: "id1", Provider: &fakeProvider{id: "id1", err: true}, CertificateAuthority: []byte("id1")},
+		serviceCertValidityDuration: time.Hour,
+		msgBroker:                   msgBroker,
+	}
+
+	// Seed the cache directly since signingIssuer always fails IssueCertificate.
+	cnPrefix := "foo"
+	cert := &Certificate{CommonName: CommonName(cnPrefix), cnPrefix: cnPrefix, IssuedAt: time.Now(), Expiration: time.Now().Add(-time.Hour)}
+	assert.NoError(cm.cache.Put(context.Background(), CommonName(cnPrefix), cert))
+
+	failed := msgBroker.GetCertPubSub().Sub(announcements.CertificateRotationFailed.String())
+
+	cm.Start(RotationPolicy{RenewBefore: 2.0 / 3.0, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}, stop)
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-failed:
+		case <-time.After(time.Second):
+			t.Fatalf("expected %d failed-rotation announcements, got %d", 3, i)
+		}
+	}
+
+	cm.rotationMu.Lock()
+	failures := cm.rotationStates[cnPrefix].failures
+	cm.rotationMu.Unlock()
+	assert.GreaterOrEqual(failures, 3, "repeated failures should be tracked so backoff keeps growing, not busy-loop at zero")
+}
@@ -0,0 +1,137 @@
+package certificate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// secretCertDataKey is the Secret data key the JSON-encoded certificate is stored under.
+const secretCertDataKey = "certificate.json"
+
+// secretCNHashLabel labels each Secret with the hash of the CommonName it
+// caches, so List can find them all with a label selector.
+const secretCNHashLabel = "cert.openservicemesh.io/cn-hash"
+
+// SecretCache implements Cache by persisting each certificate as a Kubernetes
+// Secret in the control plane namespace. Like DirCache, it exists so
+// osm-controller can survive a restart without re-issuing, and thereby
+// triggering a mass Envoy churn for, every certificate in the mesh -- this
+// variant is for HA deployments where the replicas don't share a filesystem.
+type SecretCache struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+}
+
+// NewSecretCache returns a Cache backed by Kubernetes Secrets in namespace.
+func NewSecretCache(kubeClient kubernetes.Interface, namespace string) *SecretCache {
+	return &SecretCache{kubeClient: kubeClient, namespace: namespace}
+}
+
+func secretNameForCN(cn CommonName) string {
+	sum := sha256.Sum256([]byte(cn))
+	return "osm-cert-" + hex.EncodeToString(sum[:16])
+}
+
+// Get implements Cache.
+func (s *SecretCache) Get(ctx context.Context, cn CommonName) (*Certificate, error) {
+	secret, err := s.kubeClient.CoreV1().Secrets(s.namespace).Get(ctx, secretNameForCN(cn), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	raw, ok := secret.Data[secretCertDataKey]
+	if !ok {
+		return nil, fmt.Errorf("%s: corrupt cache entry: missing %s", secret.Name, secretCertDataKey)
+	}
+
+	var cert Certificate
+	if err := json.Unmarshal(raw, &cert); err != nil {
+		return nil, fmt.Errorf("%s: corrupt cache entry: %w", secret.Name, err)
+	}
+	return &cert, nil
+}
+
+// Put implements Cache.
+func (s *SecretCache) Put(ctx context.Context, cn CommonName, cert *Certificate) error {
+	raw, err := json.Marshal(cert)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256([]byte(cn))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretNameForCN(cn),
+			Namespace: s.namespace,
+			Labels:    map[string]string{secretCNHashLabel: hex.EncodeToString(sum[:16])},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{secretCertDataKey: raw},
+	}
+
+	client := s.kubeClient.CoreV1().Secrets(s.namespace)
+	if _, err := client.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+
+		// The API server rejects an Update whose ResourceVersion is unset, so
+		// the existing Secret must be fetched first to carry its
+		// ResourceVersion forward -- a blind overwrite only works against the
+		// fake clientset used in tests, not a real API server.
+		existing, err := client.Get(ctx, secret.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		secret.ResourceVersion = existing.ResourceVersion
+		if _, err := client.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete implements Cache.
+func (s *SecretCache) Delete(ctx context.Context, cn CommonName) error {
+	err := s.kubeClient.CoreV1().Secrets(s.namespace).Delete(ctx, secretNameForCN(cn), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// List implements Cache.
+func (s *SecretCache) List(ctx context.Context) ([]*Certificate, error) {
+	list, err := s.kubeClient.CoreV1().Secrets(s.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: secretCNHashLabel,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	certs := make([]*Certificate, 0, len(list.Items))
+	for i := range list.Items {
+		raw, ok := list.Items[i].Data[secretCertDataKey]
+		if !ok {
+			continue
+		}
+
+		var cert Certificate
+		if err := json.Unmarshal(raw, &cert); err != nil {
+			return nil, fmt.Errorf("%s: corrupt cache entry: %w", list.Items[i].Name, err)
+		}
+		certs = append(certs, &cert)
+	}
+	return certs, nil
+}
@@ -0,0 +1,40 @@
+// Package messaging implements the internal pub/sub broker used to announce
+// control plane events, such as certificate rotation, to interested subscribers.
+package messaging
+
+import (
+	"github.com/cskr/pubsub"
+)
+
+// certPubSubCapacity is the buffer size used for the certificate events topic.
+const certPubSubCapacity = 1024
+
+// Broker implements the internal pub/sub system used to pass events between
+// components of the control plane.
+type Broker struct {
+	certPubSub *pubsub.PubSub
+	stop       <-chan struct{}
+}
+
+// NewBroker returns a new message broker instance. The broker is stopped,
+// and its subscriptions torn down, when the given stop channel is closed.
+func NewBroker(stop <-chan struct{}) *Broker {
+	b := &Broker{
+		certPubSub: pubsub.New(certPubSubCapacity),
+		stop:       stop,
+	}
+
+	go b.runCertPubSubShutdown()
+
+	return b
+}
+
+// GetCertPubSub returns the PubSub instance corresponding to certificate events.
+func (b *Broker) GetCertPubSub() *pubsub.PubSub {
+	return b.certPubSub
+}
+
+func (b *Broker) runCertPubSubShutdown() {
+	<-b.stop
+	b.certPubSub.Shutdown()
+}